@@ -0,0 +1,382 @@
+// Command steg is a thin CLI over pkg/steg: it wires flags to a Codec
+// (optionally decorated with encryption and/or FEC) and drives the image
+// I/O, but all of the actual steganography lives in the library so other
+// Go programs can embed/extract messages without shelling out to this
+// binary.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/samber/lo"
+	"github.com/sirupsen/logrus"
+
+	"github.com/slham/steg/pkg/steg"
+)
+
+var validExtensions = []string{"jpg", "png"}
+
+var validFECLevels = []string{"none", "light", "paranoid"}
+
+func main() {
+	var verbose bool
+	var encode bool
+	var decode bool
+	var imagePath string
+	var format string
+	var outPath string
+	var secret string
+	var secretPath string
+	var passphrase string
+	var fecLevel string
+	var angecrypt bool
+	var hiddenImagePath string
+	var coverImagePath string
+	var angecryptKey string
+
+	flag.BoolVar(&verbose, "verbose", false, "verbose logging")
+	flag.BoolVar(&encode, "encode", false, "encode image file")
+	flag.BoolVar(&decode, "decode", false, "decode image file")
+	flag.StringVar(&imagePath, "image-path", "", "path to image, or - for stdin/stdout")
+	flag.StringVar(&format, "format", "", "image format (png|jpg), required when -image-path is -")
+	flag.StringVar(&outPath, "out", "", "output image path, or - for stdout (default encoded_image.<ext>)")
+	flag.StringVar(&secret, "secret", "", "secret message")
+	flag.StringVar(&secretPath, "secret-path", "", "path to secret file, or - for stdin")
+	flag.StringVar(&passphrase, "passphrase", "", "passphrase to encrypt/decrypt the secret message with (Argon2id + ChaCha20-Poly1305)")
+	flag.StringVar(&fecLevel, "fec-level", "none", "reed-solomon FEC level to protect the payload against lossy re-encoding: none|light|paranoid")
+	flag.BoolVar(&angecrypt, "angecrypt", false, "build a PNG polyglot: produces an image that renders as -cover-image but AES-CBC-decrypts (with -key) to -hidden-image")
+	flag.StringVar(&hiddenImagePath, "hidden-image", "", "path to the PNG that should be recoverable by AES-CBC-decrypting the -angecrypt output")
+	flag.StringVar(&coverImagePath, "cover-image", "", "path to the PNG the -angecrypt output should visually render as")
+	flag.StringVar(&angecryptKey, "key", "", "16-byte AES-128 key, hex-encoded, used for -angecrypt")
+
+	flag.Parse()
+
+	if verbose {
+		logrus.SetLevel(logrus.DebugLevel)
+	}
+
+	if angecrypt {
+		runAngecrypt(hiddenImagePath, coverImagePath, angecryptKey, outPathOr(outPath, "angecrypt_output.png"))
+		return
+	}
+
+	if !encode && !decode || encode && decode {
+		logrus.Warnf("must pass either -encode or -decode")
+		logrus.Infof("exiting")
+		return
+	}
+
+	if imagePath == "" {
+		logrus.Warnf("must pass -image-path")
+		logrus.Infof("exiting")
+		return
+	}
+
+	extension, err := imageExtension(imagePath, format)
+	if err != nil {
+		logrus.WithError(err).Errorf("could not determine image format")
+		logrus.Infof("exiting")
+		return
+	}
+	if !lo.Contains(validExtensions, extension) {
+		logrus.Errorf("invalid image format (%s). valid formats are (%v)", extension, validExtensions)
+		logrus.Infof("exiting")
+		return
+	}
+
+	if !lo.Contains(validFECLevels, fecLevel) {
+		logrus.Errorf("invalid fec-level (%s). valid levels are (%v)", fecLevel, validFECLevels)
+		logrus.Infof("exiting")
+		return
+	}
+
+	// DCTCodec hands libjpeg a file path, so it can't read "-"
+	// directly; spool stdin to a real temp file and use that as the
+	// source for both the codec and decodeImage.
+	sourcePath, cleanupSource, err := resolveSourcePath(imagePath, extension)
+	if err != nil {
+		logrus.WithError(err).Errorf("failed to read image from stdin")
+		logrus.Infof("exiting")
+		return
+	}
+	defer cleanupSource()
+
+	codec, err := buildCodec(extension, sourcePath, passphrase, fecLevel)
+	if err != nil {
+		logrus.WithError(err).Errorf("failed to build codec")
+		logrus.Infof("exiting")
+		return
+	}
+
+	if decode {
+		runDecode(codec, sourcePath, extension)
+		return
+	}
+
+	runEncode(codec, sourcePath, extension, outPathOr(outPath, "encoded_image."+extension), secret, secretPath)
+}
+
+// resolveSourcePath returns a real filesystem path to read imagePath's image
+// from. DCTCodec needs an actual file (it hands the path straight to
+// libjpeg), so when imagePath is "-" and extension is "jpg" this spools
+// stdin into a temp file and returns that path instead; any other format
+// can read "-" directly via openReader, so imagePath is returned unchanged.
+// The returned cleanup func removes the temp file, if one was created.
+func resolveSourcePath(imagePath, extension string) (path string, cleanup func(), err error) {
+	noop := func() {}
+	if imagePath != "-" || extension != "jpg" {
+		return imagePath, noop, nil
+	}
+
+	tmp, err := os.CreateTemp("", "steg-stdin-*.jpg")
+	if err != nil {
+		return "", noop, fmt.Errorf("%w. failed to create temp file for stdin", err)
+	}
+
+	if _, err := io.Copy(tmp, os.Stdin); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", noop, fmt.Errorf("%w. failed to spool stdin to temp file", err)
+	}
+	tmp.Close()
+
+	return tmp.Name(), func() { os.Remove(tmp.Name()) }, nil
+}
+
+// buildCodec picks the base Codec for extension (DCT for jpg, LSB for png)
+// and layers on FEC and encryption decorators as requested.
+func buildCodec(extension, imagePath, passphrase, fecLevel string) (steg.Codec, error) {
+	var codec steg.Codec
+	switch extension {
+	case "jpg":
+		codec = steg.NewDCTCodec(imagePath)
+	case "png":
+		codec = &steg.LSBCodec{}
+	default:
+		return nil, fmt.Errorf("unsupported image format (%s)", extension)
+	}
+
+	if fecLevel != "" && fecLevel != "none" {
+		codec = &steg.FECCodec{Inner: codec, Level: fecLevel}
+	}
+
+	if passphrase != "" {
+		codec = &steg.EncryptedCodec{Inner: codec, Passphrase: passphrase}
+	}
+
+	return codec, nil
+}
+
+func runDecode(codec steg.Codec, imagePath, extension string) {
+	img, err := decodeImage(imagePath, extension)
+	if err != nil {
+		logrus.WithError(err).Errorf("failed to decode image")
+		logrus.Infof("exiting")
+		return
+	}
+
+	var message strings.Builder
+	if err := codec.Extract(img, &message); err != nil {
+		logrus.WithError(err).Errorf("failed to extract secret message")
+		logrus.Infof("exiting")
+		return
+	}
+
+	logrus.Infof("Steganography completed successfully!")
+	logrus.Infof("Hidden messaage: %s", message.String())
+}
+
+func runEncode(codec steg.Codec, imagePath, extension, outPath, secret, secretPath string) {
+	if secret == "" && secretPath == "" || secret != "" && secretPath != "" {
+		logrus.Warnf("must pass -secret or -secret-path")
+		logrus.Infof("exiting")
+		return
+	}
+
+	var payload io.Reader
+	if secret != "" {
+		payload = strings.NewReader(secret)
+	} else {
+		secretFile, err := openReader(secretPath)
+		if err != nil {
+			logrus.WithError(err).Errorf("could not open secret (%s)", secretPath)
+			logrus.Infof("exiting")
+			return
+		}
+		defer secretFile.Close()
+		payload = secretFile
+	}
+
+	img, err := decodeImage(imagePath, extension)
+	if err != nil {
+		logrus.WithError(err).Errorf("failed to decode image")
+		logrus.Infof("exiting")
+		return
+	}
+
+	encodedImg, err := codec.Embed(img, payload)
+	if err != nil {
+		logrus.WithError(err).Errorf("failed to embed secret message")
+		logrus.Infof("exiting")
+		return
+	}
+
+	// DCTCodec's output must be written byte-for-byte: encoding its
+	// decoded image.Image with image/jpeg would run a fresh forward
+	// DCT/quantization pass and destroy the coefficients JSteg just set.
+	var writeErr error
+	if raw, ok := steg.RawBytes(encodedImg); ok {
+		writeErr = writeRaw(outPath, raw)
+	} else {
+		writeErr = encodeImage(outPath, extension, encodedImg)
+	}
+	if writeErr != nil {
+		logrus.WithError(writeErr).Errorf("failed to write encoded image")
+		logrus.Infof("exiting")
+		return
+	}
+
+	logrus.Infof("Steganography completed successfully!")
+	logrus.Infof("file can be found at %s", outPath)
+}
+
+func runAngecrypt(hiddenPath, coverPath, key, outPath string) {
+	if hiddenPath == "" || coverPath == "" || key == "" {
+		logrus.Warnf("must pass -hidden-image, -cover-image, and -key with -angecrypt")
+		logrus.Infof("exiting")
+		return
+	}
+
+	if err := steg.Angecrypt(hiddenPath, coverPath, key, outPath); err != nil {
+		logrus.WithError(err).Errorf("failed to build angecrypt image")
+		logrus.Infof("exiting")
+		return
+	}
+
+	logrus.Infof("Steganography completed successfully!")
+	logrus.Infof("file can be found at %s", outPath)
+}
+
+// imageExtension returns the image format to use: format if imagePath is
+// "-" (stdin/stdout has no extension to sniff), otherwise the path's
+// extension via filepath.Ext, which - unlike splitting on "." - handles
+// filenames with multiple dots (e.g. foo.bar.png).
+func imageExtension(imagePath, format string) (string, error) {
+	if imagePath == "-" {
+		if format == "" {
+			return "", fmt.Errorf("-format is required when -image-path is -")
+		}
+		return strings.ToLower(format), nil
+	}
+
+	ext := strings.TrimPrefix(filepath.Ext(imagePath), ".")
+	if ext == "" {
+		return "", fmt.Errorf("invalid image-path (%s): no file extension", imagePath)
+	}
+
+	return strings.ToLower(ext), nil
+}
+
+func outPathOr(outPath, fallback string) string {
+	if outPath == "" {
+		return fallback
+	}
+	return outPath
+}
+
+func openReader(path string) (io.ReadCloser, error) {
+	if path == "-" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	return os.Open(path)
+}
+
+func decodeImage(imagePath, extension string) (image.Image, error) {
+	logrus.Debugf("decoding image")
+
+	imageFile, err := openReader(imagePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w. failed to open image file (%s)", err, imagePath)
+	}
+	defer imageFile.Close()
+
+	switch extension {
+	case "jpg":
+		img, err := jpeg.Decode(imageFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w. failed to decode file", err)
+		}
+		return img, nil
+	case "png":
+		img, err := png.Decode(imageFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w. failed to decode file", err)
+		}
+		return img, nil
+	default:
+		return nil, fmt.Errorf("unsupported image format (%s)", extension)
+	}
+}
+
+func encodeImage(outPath, extension string, img image.Image) error {
+	logrus.Debugf("encoding image")
+
+	encodedFile, err := openWriter(outPath)
+	if err != nil {
+		return fmt.Errorf("%w. failed to create file (%s)", err, outPath)
+	}
+	defer encodedFile.Close()
+
+	switch extension {
+	case "jpg":
+		if err := jpeg.Encode(encodedFile, img, nil); err != nil {
+			return fmt.Errorf("%w. failed to encode file", err)
+		}
+	case "png":
+		if err := png.Encode(encodedFile, img); err != nil {
+			return fmt.Errorf("%w. failed to encode file", err)
+		}
+	default:
+		return fmt.Errorf("unsupported image format (%s)", extension)
+	}
+
+	return nil
+}
+
+// writeRaw writes raw (a DCTCodec's exact encoded JPEG bytes) to outPath
+// verbatim, bypassing image/jpeg entirely - see steg.RawBytes.
+func writeRaw(outPath string, raw []byte) error {
+	logrus.Debugf("writing raw jpeg bytes")
+
+	out, err := openWriter(outPath)
+	if err != nil {
+		return fmt.Errorf("%w. failed to create file (%s)", err, outPath)
+	}
+	defer out.Close()
+
+	_, err = out.Write(raw)
+	return err
+}
+
+func openWriter(outPath string) (io.WriteCloser, error) {
+	if outPath == "-" {
+		return nopWriteCloser{os.Stdout}, nil
+	}
+	return os.Create(outPath)
+}
+
+// nopWriteCloser adapts os.Stdout (which we must not close) to
+// io.WriteCloser so encodeImage can treat file and stdout output the same.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
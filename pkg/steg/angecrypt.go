@@ -0,0 +1,138 @@
+package steg
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"os"
+)
+
+// pngSignature is the 8-byte magic every PNG file starts with.
+var pngSignature = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+// angecryptChunkType is the fake, ancillary/private/safe-to-copy PNG chunk
+// type (lowercase first letter) used to make PNG decoders skip over the
+// encrypted hidden image instead of choking on it.
+var angecryptChunkType = []byte("rmll")
+
+// Angecrypt implements the AngeCryption PNG polyglot technique: it writes
+// outPath, a file that renders as a valid PNG of coverPath, but which -
+// when AES-CBC-decrypted with keyHex and the IV this function derives -
+// reproduces hiddenPath byte-for-byte (followed by padding and garbage).
+//
+// The trick is choosing the IV so the first AES block of hiddenPath's own
+// CBC ciphertext is forced to equal a PNG signature followed by a fake
+// ancillary chunk header ("rmll") whose declared length swallows the rest
+// of the encrypted hidden image; PNG decoders then skip straight to
+// coverPath's real chunks, appended right after.
+func Angecrypt(hiddenPath, coverPath, keyHex, outPath string) error {
+	key, err := hex.DecodeString(keyHex)
+	if err != nil || len(key) != 16 {
+		return fmt.Errorf("key must be 32 hex characters (16 bytes) for AES-128")
+	}
+
+	cipherBlock, err := aes.NewCipher(key)
+	if err != nil {
+		return fmt.Errorf("%w. failed to construct AES cipher", err)
+	}
+
+	hidden, err := os.ReadFile(hiddenPath)
+	if err != nil {
+		return fmt.Errorf("%w. failed to read hidden image (%s)", err, hiddenPath)
+	}
+
+	cover, err := os.ReadFile(coverPath)
+	if err != nil {
+		return fmt.Errorf("%w. failed to read cover image (%s)", err, coverPath)
+	}
+	if len(cover) < len(pngSignature) {
+		return fmt.Errorf("cover image is too small to be a valid PNG")
+	}
+
+	hiddenPadded := padTo16(hidden)
+
+	fakeBlock := make([]byte, aes.BlockSize)
+	copy(fakeBlock, pngSignature)
+	copy(fakeBlock[8:12], uint32ToBytes(uint32(len(hiddenPadded)-aes.BlockSize)))
+	copy(fakeBlock[12:16], angecryptChunkType)
+
+	decryptedFakeBlock := make([]byte, aes.BlockSize)
+	cipherBlock.Decrypt(decryptedFakeBlock, fakeBlock)
+
+	iv := xorBytes(decryptedFakeBlock, hiddenPadded[:aes.BlockSize])
+
+	encryptedHidden := cbcEncrypt(cipherBlock, iv, hiddenPadded)
+
+	// combined's first block already equals fakeBlock by construction (iv
+	// was chosen so CBC-encrypting hiddenPadded's first block reproduces
+	// it), so combined itself - not a further transform of it - is the
+	// polyglot: it opens as the PNG signature plus the fake "rmll" chunk
+	// (which swallows encryptedHidden), followed by coverPath's real
+	// chunks, while AES-CBC-decrypting it with key/iv reproduces hidden
+	// byte-for-byte.
+	//
+	// PNG chunk CRCs cover the chunk type and data, not its length prefix,
+	// so the fake chunk's CRC is computed over angecryptChunkType plus the
+	// data that follows the fake header block - not over encryptedHidden's
+	// first block - or PNG decoders that verify chunk checksums reject it.
+	chunkData := encryptedHidden[aes.BlockSize:]
+	chunkCRC := crc32.NewIEEE()
+	chunkCRC.Write(angecryptChunkType)
+	chunkCRC.Write(chunkData)
+
+	combined := make([]byte, 0, len(encryptedHidden)+4+len(cover)-len(pngSignature))
+	combined = append(combined, encryptedHidden...)
+	combined = append(combined, uint32ToBytes(chunkCRC.Sum32())...)
+	combined = append(combined, cover[len(pngSignature):]...)
+
+	// Right-pad to a multiple of the AES block size so the whole file -
+	// not just the encryptedHidden prefix - can be AES-CBC-decrypted with
+	// (key, iv) in one CryptBlocks call, per the advertised "decrypt the
+	// whole result" workflow. PNG decoders ignore trailing bytes after
+	// IEND, so this doesn't affect combined's validity as a PNG.
+	combined = padTo16(combined)
+
+	if err := os.WriteFile(outPath, combined, 0644); err != nil {
+		return fmt.Errorf("%w. failed to write angecrypted image (%s)", err, outPath)
+	}
+
+	return nil
+}
+
+// padTo16 right-pads data with zero bytes up to the next multiple of
+// aes.BlockSize.
+func padTo16(data []byte) []byte {
+	remainder := len(data) % aes.BlockSize
+	if remainder == 0 {
+		return data
+	}
+
+	padded := make([]byte, len(data), len(data)+aes.BlockSize-remainder)
+	copy(padded, data)
+	return append(padded, make([]byte, aes.BlockSize-remainder)...)
+}
+
+// xorBytes XORs two equal-length byte slices.
+func xorBytes(a, b []byte) []byte {
+	out := make([]byte, len(a))
+	for i := range a {
+		out[i] = a[i] ^ b[i]
+	}
+	return out
+}
+
+func cbcEncrypt(cipherBlock cipher.Block, iv, plaintext []byte) []byte {
+	mode := cipher.NewCBCEncrypter(cipherBlock, iv)
+	ciphertext := make([]byte, len(plaintext))
+	mode.CryptBlocks(ciphertext, plaintext)
+	return ciphertext
+}
+
+func cbcDecrypt(cipherBlock cipher.Block, iv, ciphertext []byte) []byte {
+	mode := cipher.NewCBCDecrypter(cipherBlock, iv)
+	plaintext := make([]byte, len(ciphertext))
+	mode.CryptBlocks(plaintext, ciphertext)
+	return plaintext
+}
@@ -0,0 +1,89 @@
+// Package steg implements image steganography: hiding arbitrary payloads
+// inside images and recovering them again. It exposes a pluggable Codec
+// interface so callers can choose spatial-domain LSB embedding, JPEG
+// DCT-coefficient embedding, or wrap either one in encryption/FEC, without
+// shelling out to the cmd/steg CLI.
+package steg
+
+import (
+	"image"
+	"io"
+)
+
+// Codec embeds a payload into a cover image and extracts it back out.
+// Implementations decide where in the image bits live (pixels, DCT
+// coefficients, ...); decorators like EncryptedCodec and FECCodec wrap an
+// inner Codec to transform the payload before/after it reaches one.
+type Codec interface {
+	// Embed returns a new image with payload hidden inside cover.
+	Embed(cover image.Image, payload io.Reader) (image.Image, error)
+
+	// Extract recovers a payload previously hidden in stego and writes it
+	// to out.
+	Extract(stego image.Image, out io.Writer) error
+
+	// Capacity estimates, in bytes, how large a payload img can hold.
+	Capacity(img image.Image) int
+}
+
+// Channel selects which color channels a spatial codec reads and writes.
+// Channels can be OR'd together, e.g. ChannelR|ChannelB.
+type Channel int
+
+const (
+	ChannelR Channel = 1 << iota
+	ChannelG
+	ChannelB
+)
+
+// AllChannels is the default channel mask: embed across R, G and B.
+const AllChannels = ChannelR | ChannelG | ChannelB
+
+// lengthPrefixSize is the number of bytes used to store the big-endian
+// length of an embedded frame.
+const lengthPrefixSize = 4
+
+// headerRepeats is how many redundant copies of the length header Embed
+// writes ahead of a codec's payload. The header has no error correction
+// of its own and sits at the very front of the bitstream, exactly where
+// lossy re-encoding/resaving does the most damage, so a single corrupted
+// copy must not be able to take down the whole extraction; decodeHeader
+// recovers the true length by a per-bit majority vote across copies.
+const headerRepeats = 3
+
+// headerSize is the total size, in bytes, of the redundant length header
+// written ahead of a codec's payload.
+const headerSize = lengthPrefixSize * headerRepeats
+
+// encodeHeader returns headerSize bytes encoding n as a big-endian
+// lengthPrefixSize-byte length, repeated headerRepeats times so
+// decodeHeader can recover n even if some copies are corrupted.
+func encodeHeader(n int) []byte {
+	header := make([]byte, 0, headerSize)
+	single := []byte{byte(n >> 24), byte(n >> 16), byte(n >> 8), byte(n)}
+	for i := 0; i < headerRepeats; i++ {
+		header = append(header, single...)
+	}
+	return header
+}
+
+// decodeHeader recovers the length encodeHeader wrote from header, a
+// headerSize-byte buffer, by taking the bit a majority of the
+// headerRepeats copies agree on at each position.
+func decodeHeader(header []byte) int {
+	var single [lengthPrefixSize]byte
+	for byteIdx := 0; byteIdx < lengthPrefixSize; byteIdx++ {
+		for bit := 0; bit < 8; bit++ {
+			votes := 0
+			for copyIdx := 0; copyIdx < headerRepeats; copyIdx++ {
+				if header[copyIdx*lengthPrefixSize+byteIdx]&(1<<uint(7-bit)) != 0 {
+					votes++
+				}
+			}
+			if votes*2 > headerRepeats {
+				single[byteIdx] |= 1 << uint(7-bit)
+			}
+		}
+	}
+	return int(single[0])<<24 | int(single[1])<<16 | int(single[2])<<8 | int(single[3])
+}
@@ -0,0 +1,100 @@
+package steg
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	saltSize = 16
+
+	argon2Time    = 1
+	argon2Memory  = 64 * 1024
+	argon2Threads = 4
+	argon2KeyLen  = 32
+)
+
+// Encoder encrypts a secret message with a passphrase before it is embedded
+// in an image. The key is derived per-message with Argon2id so a random
+// salt can be stored alongside the ciphertext instead of a fixed one.
+type Encoder struct {
+	Passphrase string
+}
+
+// Decoder reverses Encoder: given the salt and nonce embedded in the image
+// it re-derives the key and authenticates/decrypts the ciphertext.
+type Decoder struct {
+	Passphrase string
+}
+
+// Encrypt derives a key from e.Passphrase and a fresh random salt, then
+// seals message with ChaCha20-Poly1305. It returns salt || nonce || ciphertext,
+// where ciphertext includes the trailing Poly1305 tag.
+func (e *Encoder) Encrypt(message []byte) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("%w. failed to generate salt", err)
+	}
+
+	key := deriveKey(e.Passphrase, salt)
+
+	aead, err := chacha20poly1305.New(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w. failed to construct aead", err)
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("%w. failed to generate nonce", err)
+	}
+
+	ciphertext := aead.Seal(nil, nonce, message, nil)
+
+	out := make([]byte, 0, len(salt)+len(nonce)+len(ciphertext))
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	out = append(out, ciphertext...)
+
+	return out, nil
+}
+
+// Decrypt reverses Encrypt: it splits salt || nonce || ciphertext out of
+// frame, re-derives the key, and opens the ciphertext.
+func (d *Decoder) Decrypt(frame []byte) ([]byte, error) {
+	aead, err := chacha20poly1305.New(make([]byte, argon2KeyLen))
+	if err != nil {
+		return nil, fmt.Errorf("%w. failed to construct aead", err)
+	}
+
+	minLen := saltSize + aead.NonceSize()
+	if len(frame) < minLen {
+		return nil, fmt.Errorf("encrypted frame too short (%d bytes)", len(frame))
+	}
+
+	salt := frame[:saltSize]
+	nonce := frame[saltSize:minLen]
+	ciphertext := frame[minLen:]
+
+	key := deriveKey(d.Passphrase, salt)
+
+	aead, err = chacha20poly1305.New(key)
+	if err != nil {
+		return nil, fmt.Errorf("%w. failed to construct aead", err)
+	}
+
+	message, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w. failed to decrypt message, wrong passphrase?", err)
+	}
+
+	return message, nil
+}
+
+// deriveKey stretches passphrase into a 32-byte ChaCha20-Poly1305 key using
+// Argon2id, keyed by salt so every encrypted message uses a unique key.
+func deriveKey(passphrase string, salt []byte) []byte {
+	return argon2.IDKey([]byte(passphrase), salt, argon2Time, argon2Memory, argon2Threads, argon2KeyLen)
+}
@@ -0,0 +1,174 @@
+package steg
+
+/*
+#cgo LDFLAGS: -ljpeg
+#include <stdlib.h>
+#include "dct.h"
+*/
+import "C"
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"os"
+	"unsafe"
+)
+
+// maxDCTFrameSize bounds the buffer used to recover a hidden message from
+// a JPEG's DCT coefficients. The real limit is however many usable AC
+// coefficients the image has; this is just a generous allocation cap.
+const maxDCTFrameSize = 16 * 1024 * 1024
+
+// DCTCodec hides payloads in a JPEG's quantized DCT coefficients via
+// JSteg (overwrite the LSB of each nonzero, non-+/-1 AC coefficient),
+// re-entropy-coding without a second forward DCT pass so writing the JPEG
+// does not re-quantize the embedded bits away - unlike LSBCodec, which
+// JPEG's own lossy re-encoding destroys. A later re-encode of the decoded
+// pixels still destroys the embedding (see rawJPEG).
+//
+// Coefficients don't survive image/jpeg's decode to image.Image, so
+// DCTCodec must be constructed with the path to the original JPEG file;
+// the cover/stego image.Image arguments to Embed/Extract are only used
+// to satisfy the Codec interface and for capacity estimates.
+type DCTCodec struct {
+	SourcePath string
+}
+
+func NewDCTCodec(sourcePath string) *DCTCodec {
+	return &DCTCodec{SourcePath: sourcePath}
+}
+
+func (c *DCTCodec) Embed(cover image.Image, payload io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w. failed to read payload", err)
+	}
+
+	tmpOut, err := os.CreateTemp("", "steg-dct-*.jpg")
+	if err != nil {
+		return nil, fmt.Errorf("%w. failed to create temp file", err)
+	}
+	tmpOut.Close()
+	defer os.Remove(tmpOut.Name())
+
+	if err := embedDCTCoefficients(c.SourcePath, tmpOut.Name(), data); err != nil {
+		return nil, err
+	}
+
+	raw, err := os.ReadFile(tmpOut.Name())
+	if err != nil {
+		return nil, fmt.Errorf("%w. failed to read embedded jpeg", err)
+	}
+
+	img, err := jpeg.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("%w. failed to decode embedded jpeg", err)
+	}
+
+	return &rawJPEG{Image: img, bytes: raw}, nil
+}
+
+// rawJPEG carries the exact encoded bytes DCTCodec.Embed produced alongside
+// the decoded image.Image the Codec interface requires. JSteg's embedding
+// lives in specific DCT coefficient values; re-encoding the decoded pixels
+// with image/jpeg runs a brand new forward DCT/quantization pass and
+// destroys them, so callers that persist a DCTCodec's output must write
+// these bytes verbatim instead of encoding the image. See RawBytes.
+type rawJPEG struct {
+	image.Image
+	bytes []byte
+}
+
+// RawBytes returns the literal encoded bytes behind img if img was produced
+// by DCTCodec.Embed, and ok=false otherwise. Writers must use these bytes
+// as-is for JPEG output; encoding img with image/jpeg instead would destroy
+// the embedded DCT coefficients.
+func RawBytes(img image.Image) (raw []byte, ok bool) {
+	r, ok := img.(*rawJPEG)
+	if !ok {
+		return nil, false
+	}
+	return r.bytes, true
+}
+
+// Extract ignores stego: JSteg coefficients must be read from the
+// original JPEG bytes, which a decoded image.Image no longer has, so
+// Extract always reads from c.SourcePath.
+func (c *DCTCodec) Extract(stego image.Image, out io.Writer) error {
+	frame, err := extractDCTCoefficients(c.SourcePath)
+	if err != nil {
+		return err
+	}
+
+	_, err = out.Write(frame)
+	return err
+}
+
+// Capacity estimates, in bytes, how large a payload Embed could hide.
+// Exactly which coefficients are usable (nonzero, not +/-1) is only known
+// once Embed actually reads them; this assumes roughly half of a block's
+// 63 AC coefficients qualify after quantization.
+func (c *DCTCodec) Capacity(img image.Image) int {
+	bounds := img.Bounds()
+	blocks := ((bounds.Dx() + 7) / 8) * ((bounds.Dy() + 7) / 8)
+	capacity := blocks*63/2/8 - headerSize
+	if capacity < 0 {
+		return 0
+	}
+	return capacity
+}
+
+// embedDCTCoefficients prefixes frame with a redundant length header (see
+// encodeHeader) and hides it inside path's JPEG DCT coefficients via
+// JSteg, writing the result to outPath.
+func embedDCTCoefficients(path, outPath string, frame []byte) error {
+	payload := make([]byte, 0, headerSize+len(frame))
+	payload = append(payload, encodeHeader(len(frame))...)
+	payload = append(payload, frame...)
+
+	cIn := C.CString(path)
+	defer C.free(unsafe.Pointer(cIn))
+	cOut := C.CString(outPath)
+	defer C.free(unsafe.Pointer(cOut))
+	cPayload := C.CBytes(payload)
+	defer C.free(cPayload)
+
+	ret := C.jsteg_embed(cIn, cOut, (*C.uchar)(cPayload), C.long(len(payload)))
+	if ret != 0 {
+		return fmt.Errorf("jsteg_embed failed (code %d): message may not fit in this jpeg's DCT coefficients", int(ret))
+	}
+
+	return nil
+}
+
+// extractDCTCoefficients reads back a JSteg-embedded frame from path's
+// JPEG DCT coefficients.
+func extractDCTCoefficients(path string) ([]byte, error) {
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+
+	buf := make([]byte, maxDCTFrameSize)
+	var outLen C.long
+
+	ret := C.jsteg_extract(cPath, (*C.uchar)(unsafe.Pointer(&buf[0])), C.long(len(buf)), &outLen)
+	if ret != 0 {
+		return nil, fmt.Errorf("jsteg_extract failed (code %d)", int(ret))
+	}
+
+	if int(outLen) < headerSize {
+		return nil, fmt.Errorf("jpeg does not contain a complete hidden message")
+	}
+
+	frameLen := decodeHeader(buf[:headerSize])
+	if frameLen < 0 || headerSize+frameLen > int(outLen) {
+		return nil, fmt.Errorf("jpeg hidden message length (%d) exceeds recovered coefficients (%d)", frameLen, int(outLen)-headerSize)
+	}
+
+	frame := make([]byte, frameLen)
+	copy(frame, buf[headerSize:headerSize+frameLen])
+
+	return frame, nil
+}
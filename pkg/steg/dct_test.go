@@ -0,0 +1,87 @@
+package steg
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// noisyJPEG writes a w x h random-noise JPEG to dir and returns its path.
+// Random per-pixel noise, unlike a flat or smoothly-varying image, keeps
+// most 8x8 blocks away from all-zero-or-+/-1 AC coefficients after
+// quantization, so the image actually has coefficients for JSteg to embed
+// into.
+func noisyJPEG(t *testing.T, dir string, w, h int) string {
+	t.Helper()
+
+	rng := rand.New(rand.NewSource(1))
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{uint8(rng.Intn(256)), uint8(rng.Intn(256)), uint8(rng.Intn(256)), 255})
+		}
+	}
+
+	path := filepath.Join(dir, "cover.jpg")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("create cover jpeg: %v", err)
+	}
+	defer f.Close()
+
+	if err := jpeg.Encode(f, img, &jpeg.Options{Quality: 90}); err != nil {
+		t.Fatalf("encode cover jpeg: %v", err)
+	}
+
+	return path
+}
+
+func TestDCTCodecRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	coverPath := noisyJPEG(t, dir, 128, 128)
+
+	cover, err := os.Open(coverPath)
+	if err != nil {
+		t.Fatalf("open cover: %v", err)
+	}
+	coverImg, err := jpeg.Decode(cover)
+	cover.Close()
+	if err != nil {
+		t.Fatalf("decode cover: %v", err)
+	}
+
+	codec := NewDCTCodec(coverPath)
+	payload := []byte("the secret lives in the DCT coefficients now")
+
+	encodedImg, err := codec.Embed(coverImg, bytes.NewReader(payload))
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+
+	raw, ok := RawBytes(encodedImg)
+	if !ok {
+		t.Fatalf("Embed's result does not carry its raw encoded bytes")
+	}
+
+	outPath := filepath.Join(dir, "out.jpg")
+	if err := os.WriteFile(outPath, raw, 0644); err != nil {
+		t.Fatalf("write embedded jpeg: %v", err)
+	}
+
+	// Extract ignores its stego argument - it always reads c.SourcePath -
+	// so it must be constructed against outPath, not fed coverImg/nil here.
+	var got bytes.Buffer
+	extractCodec := NewDCTCodec(outPath)
+	if err := extractCodec.Extract(nil, &got); err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+
+	if !bytes.Equal(got.Bytes(), payload) {
+		t.Fatalf("round-trip mismatch: got %q, want %q", got.Bytes(), payload)
+	}
+}
@@ -0,0 +1,57 @@
+package steg
+
+import (
+	"bytes"
+	"image"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// EncryptedCodec decorates another Codec so the payload is encrypted with
+// a passphrase (Argon2id + ChaCha20-Poly1305, see Encoder/Decoder) before
+// it reaches the inner codec, and decrypted after the inner codec
+// extracts it.
+type EncryptedCodec struct {
+	Inner      Codec
+	Passphrase string
+}
+
+func (c *EncryptedCodec) Embed(cover image.Image, payload io.Reader) (image.Image, error) {
+	message, err := io.ReadAll(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	encoder := &Encoder{Passphrase: c.Passphrase}
+	frame, err := encoder.Encrypt(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Inner.Embed(cover, bytes.NewReader(frame))
+}
+
+func (c *EncryptedCodec) Extract(stego image.Image, out io.Writer) error {
+	var frame bytes.Buffer
+	if err := c.Inner.Extract(stego, &frame); err != nil {
+		return err
+	}
+
+	decoder := &Decoder{Passphrase: c.Passphrase}
+	message, err := decoder.Decrypt(frame.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = out.Write(message)
+	return err
+}
+
+func (c *EncryptedCodec) Capacity(img image.Image) int {
+	capacity := c.Inner.Capacity(img) - saltSize - chacha20poly1305.NonceSize - chacha20poly1305.Overhead
+	if capacity < 0 {
+		return 0
+	}
+	return capacity
+}
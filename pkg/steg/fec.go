@@ -0,0 +1,226 @@
+package steg
+
+import (
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"io"
+
+	"github.com/klauspost/reedsolomon"
+)
+
+// fecHeaderSize is the size, in bytes, of the original-data-length header
+// prepended to data before Reed-Solomon splits it into shards. Folding it
+// into the RS-protected region, rather than writing it unprotected ahead
+// of the shards, means it survives the same corruption the shards do.
+const fecHeaderSize = 4
+
+// crcSize is the size of the per-shard CRC32 trailer used to detect which
+// shards were corrupted by lossy re-encoding before reconstruction.
+const crcSize = 4
+
+// fecConfig describes a Reed-Solomon (dataShards, parityShards) split.
+type fecConfig struct {
+	DataShards   int
+	ParityShards int
+}
+
+// fecLevels maps the -fec-level flag to a shard configuration. "none"
+// disables FEC entirely; "light" and "paranoid" trade capacity for
+// resilience to bit flips introduced by re-encoding/resaving.
+var fecLevels = map[string]fecConfig{
+	"none":     {DataShards: 0, ParityShards: 0},
+	"light":    {DataShards: 128, ParityShards: 8},
+	"paranoid": {DataShards: 16, ParityShards: 48},
+}
+
+// fecEncode wraps data in a Reed-Solomon code per level. data is prefixed
+// with its own 4-byte length before splitting, so that header is carried
+// inside the RS-protected shards rather than sitting unprotected ahead of
+// them. The returned frame is (shard || 4-byte shard CRC32) for each of
+// dataShards+parityShards shards; the shard size isn't stored anywhere
+// because it's exactly len(frame)/(dataShards+parityShards) - crcSize.
+func fecEncode(level string, data []byte) ([]byte, error) {
+	cfg, ok := fecLevels[level]
+	if !ok {
+		return nil, fmt.Errorf("unknown fec-level (%s)", level)
+	}
+	if cfg.DataShards == 0 {
+		return data, nil
+	}
+
+	enc, err := reedsolomon.New(cfg.DataShards, cfg.ParityShards)
+	if err != nil {
+		return nil, fmt.Errorf("%w. failed to construct reed-solomon encoder", err)
+	}
+
+	combined := make([]byte, 0, fecHeaderSize+len(data))
+	combined = append(combined, uint32ToBytes(uint32(len(data)))...)
+	combined = append(combined, data...)
+
+	dataShards, err := enc.Split(combined)
+	if err != nil {
+		return nil, fmt.Errorf("%w. failed to split data into shards", err)
+	}
+
+	total := cfg.DataShards + cfg.ParityShards
+	shardSize := len(dataShards[0])
+
+	shards := make([][]byte, total)
+	copy(shards, dataShards)
+	for i := cfg.DataShards; i < total; i++ {
+		shards[i] = make([]byte, shardSize)
+	}
+
+	if err := enc.Encode(shards); err != nil {
+		return nil, fmt.Errorf("%w. failed to encode parity shards", err)
+	}
+
+	frame := make([]byte, 0, total*(shardSize+crcSize))
+	for _, shard := range shards {
+		frame = append(frame, shard...)
+		frame = append(frame, uint32ToBytes(crc32.ChecksumIEEE(shard))...)
+	}
+
+	return frame, nil
+}
+
+// fecDecode reverses fecEncode: it checks each shard's CRC32, treats any
+// mismatched shard as an erasure, reconstructs via Reed-Solomon, and
+// rejoins the original data.
+func fecDecode(level string, frame []byte) ([]byte, error) {
+	cfg, ok := fecLevels[level]
+	if !ok {
+		return nil, fmt.Errorf("unknown fec-level (%s)", level)
+	}
+	if cfg.DataShards == 0 {
+		return frame, nil
+	}
+
+	total := cfg.DataShards + cfg.ParityShards
+	if len(frame)%total != 0 {
+		return nil, fmt.Errorf("fec frame (%d bytes) is not a multiple of %d shards", len(frame), total)
+	}
+
+	recordSize := len(frame) / total
+	shardSize := recordSize - crcSize
+	if shardSize <= 0 {
+		return nil, fmt.Errorf("fec frame too short for %d shards", total)
+	}
+
+	shards := make([][]byte, total)
+	for i := 0; i < total; i++ {
+		record := frame[i*recordSize : (i+1)*recordSize]
+		shard := record[:shardSize]
+		wantCRC := bytesToUint32(record[shardSize:])
+		if crc32.ChecksumIEEE(shard) == wantCRC {
+			shards[i] = shard
+		}
+	}
+
+	enc, err := reedsolomon.New(cfg.DataShards, cfg.ParityShards)
+	if err != nil {
+		return nil, fmt.Errorf("%w. failed to construct reed-solomon encoder", err)
+	}
+
+	if err := enc.Reconstruct(shards); err != nil {
+		return nil, fmt.Errorf("%w. failed to reconstruct shards", err)
+	}
+
+	// Join the full, still-padded combined buffer (header + data + the
+	// zero padding Split added) rather than trimming to a length read
+	// from anywhere unprotected - the one length that matters, data's
+	// own, lives inside this buffer and survived reconstruction with it.
+	paddedLen := shardSize * cfg.DataShards
+	combined := make([]byte, 0, paddedLen)
+	buf := &sliceWriter{buf: &combined}
+	if err := enc.Join(buf, shards, paddedLen); err != nil {
+		return nil, fmt.Errorf("%w. failed to join shards", err)
+	}
+
+	if len(combined) < fecHeaderSize {
+		return nil, fmt.Errorf("reconstructed fec frame too short (%d bytes)", len(combined))
+	}
+
+	dataLen := int(bytesToUint32(combined[:fecHeaderSize]))
+	if dataLen < 0 || fecHeaderSize+dataLen > len(combined) {
+		return nil, fmt.Errorf("fec data length (%d) exceeds reconstructed frame (%d)", dataLen, len(combined)-fecHeaderSize)
+	}
+
+	return combined[fecHeaderSize : fecHeaderSize+dataLen], nil
+}
+
+// sliceWriter adapts a *[]byte to io.Writer so Join can stream into it
+// without an intermediate bytes.Buffer.
+type sliceWriter struct {
+	buf *[]byte
+}
+
+func (w *sliceWriter) Write(p []byte) (int, error) {
+	*w.buf = append(*w.buf, p...)
+	return len(p), nil
+}
+
+func uint32ToBytes(v uint32) []byte {
+	return []byte{byte(v >> 24), byte(v >> 16), byte(v >> 8), byte(v)}
+}
+
+func bytesToUint32(b []byte) uint32 {
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
+
+// FECCodec decorates another Codec so the payload survives lossy
+// re-encoding: it's wrapped in a Reed-Solomon code (see fecEncode) before
+// reaching the inner codec, and reconstructed after the inner codec
+// extracts it.
+type FECCodec struct {
+	Inner Codec
+	Level string
+}
+
+func (c *FECCodec) Embed(cover image.Image, payload io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	frame, err := fecEncode(c.Level, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Inner.Embed(cover, bytes.NewReader(frame))
+}
+
+func (c *FECCodec) Extract(stego image.Image, out io.Writer) error {
+	var frame bytes.Buffer
+	if err := c.Inner.Extract(stego, &frame); err != nil {
+		return err
+	}
+
+	data, err := fecDecode(c.Level, frame.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = out.Write(data)
+	return err
+}
+
+func (c *FECCodec) Capacity(img image.Image) int {
+	cfg, ok := fecLevels[c.Level]
+	if !ok || cfg.DataShards == 0 {
+		return c.Inner.Capacity(img)
+	}
+
+	// Roughly dataShards/(dataShards+parityShards) of the inner capacity
+	// carries data, less the length header; the per-shard CRC32s and the
+	// shard padding are not subtracted, so this is an upper estimate.
+	usableRatio := float64(cfg.DataShards) / float64(cfg.DataShards+cfg.ParityShards)
+	capacity := int(float64(c.Inner.Capacity(img))*usableRatio) - fecHeaderSize
+	if capacity < 0 {
+		return 0
+	}
+	return capacity
+}
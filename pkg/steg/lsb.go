@@ -0,0 +1,216 @@
+package steg
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+
+	"github.com/sirupsen/logrus"
+)
+
+// bppCandidates are the bit depths considered when picking how many LSBs
+// per channel to use, smallest (least distortion) first.
+var bppCandidates = []int{1, 2, 3, 4}
+
+// bppHeaderMask isolates the 3 bits of the alpha channel of pixel (0, 0)
+// that store the chosen bit depth (1-4 fits comfortably in 3 bits).
+const bppHeaderMask = 0x07
+
+// LSBCodec hides payloads in the least significant bits of an image's
+// pixels. It automatically picks the smallest bits-per-channel in
+// {1,2,3,4} that fits the payload and records that choice in a 3-bit
+// header in the alpha channel of pixel (0, 0). Channels restricts which
+// of R, G, B carry payload bits; the zero value means AllChannels.
+type LSBCodec struct {
+	Channels Channel
+}
+
+func (c *LSBCodec) channels() []func(*color.RGBA) *uint8 {
+	mask := c.Channels
+	if mask == 0 {
+		mask = AllChannels
+	}
+
+	var channels []func(*color.RGBA) *uint8
+	if mask&ChannelR != 0 {
+		channels = append(channels, func(p *color.RGBA) *uint8 { return &p.R })
+	}
+	if mask&ChannelG != 0 {
+		channels = append(channels, func(p *color.RGBA) *uint8 { return &p.G })
+	}
+	if mask&ChannelB != 0 {
+		channels = append(channels, func(p *color.RGBA) *uint8 { return &p.B })
+	}
+
+	return channels
+}
+
+// getMinBits picks the smallest bits-per-channel in bppCandidates whose
+// capacity across pixelCount pixels and numChannels channels can hold
+// totalBytes.
+func getMinBits(pixelCount, numChannels, totalBytes int) (int, error) {
+	for _, bpp := range bppCandidates {
+		capacity := pixelCount * numChannels * bpp / 8
+		if totalBytes <= capacity {
+			return bpp, nil
+		}
+	}
+
+	return 0, fmt.Errorf("message (%d bytes) is too large to fit at any bit depth", totalBytes)
+}
+
+// Capacity returns, in bytes, the largest payload Embed could hide in img
+// at the maximum supported bit depth (4 bits per channel).
+func (c *LSBCodec) Capacity(img image.Image) int {
+	bounds := img.Bounds()
+	pixelCount := bounds.Dx() * bounds.Dy()
+	maxBpp := bppCandidates[len(bppCandidates)-1]
+	capacity := pixelCount*len(c.channels())*maxBpp/8 - headerSize
+	if capacity < 0 {
+		return 0
+	}
+	return capacity
+}
+
+// Embed hides payload in cover using LSB steganography. The payload is
+// framed as a redundant length header (see encodeHeader) followed by the
+// raw payload bytes, so Extract can stop at an exact byte count instead of
+// scanning for a terminator - this also lets the payload contain 0x00.
+func (c *LSBCodec) Embed(cover image.Image, payload io.Reader) (image.Image, error) {
+	data, err := io.ReadAll(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%w. failed to read payload", err)
+	}
+
+	bounds := cover.Bounds()
+	encodedImg := image.NewRGBA(bounds)
+
+	frame := make([]byte, 0, headerSize+len(data))
+	frame = append(frame, encodeHeader(len(data))...)
+	frame = append(frame, data...)
+
+	channels := c.channels()
+	pixelCount := bounds.Dx() * bounds.Dy()
+	bpp, err := getMinBits(pixelCount, len(channels), len(frame))
+	if err != nil {
+		return nil, err
+	}
+	logrus.Debugf("embedding %d bytes at %d bits per channel across %d channels", len(data), bpp, len(channels))
+
+	mask := uint8(1<<uint(bpp) - 1)
+	bitIndex := 0
+	totalBits := len(frame) * 8
+	isFirstPixel := true
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := cover.At(x, y).RGBA()
+			encodedColor := color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), uint8(a >> 8)}
+
+			for _, channel := range channels {
+				if bitIndex >= totalBits {
+					break
+				}
+
+				ptr := channel(&encodedColor)
+				bits := readBits(frame, bitIndex, bpp)
+				*ptr = (*ptr &^ mask) | (bits & mask)
+				bitIndex += bpp
+			}
+
+			if isFirstPixel {
+				encodedColor.A = (encodedColor.A &^ bppHeaderMask) | uint8(bpp&bppHeaderMask)
+				isFirstPixel = false
+			}
+
+			encodedImg.Set(x, y, encodedColor)
+		}
+	}
+
+	return encodedImg, nil
+}
+
+// Extract recovers a payload previously hidden in stego via Embed.
+func (c *LSBCodec) Extract(stego image.Image, out io.Writer) error {
+	bounds := stego.Bounds()
+
+	_, _, _, a := stego.At(bounds.Min.X, bounds.Min.Y).RGBA()
+	bpp := int(uint8(a>>8) & bppHeaderMask)
+	if bpp < 1 || bpp > 4 {
+		return fmt.Errorf("invalid bits-per-channel header (%d)", bpp)
+	}
+	mask := uint8(1<<uint(bpp) - 1)
+
+	channels := c.channels()
+	headerBits := headerSize * 8
+	accum := &bitAccumulator{}
+	bitIndex := 0
+	frameLen := -1
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := stego.At(x, y).RGBA()
+			pixel := color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), uint8(a >> 8)}
+
+			for _, channel := range channels {
+				value := *channel(&pixel)
+				accum.writeBits(bitIndex, value&mask, bpp)
+				bitIndex += bpp
+
+				if frameLen < 0 && bitIndex >= headerBits {
+					frameLen = decodeHeader(accum.buf[:headerSize])
+				}
+
+				if frameLen >= 0 && bitIndex >= headerBits+frameLen*8 {
+					_, err := out.Write(accum.buf[headerSize : headerSize+frameLen])
+					return err
+				}
+			}
+		}
+	}
+
+	return fmt.Errorf("image does not contain a complete hidden message")
+}
+
+// readBits reads the n most significant bits starting at bitIndex from
+// data, treating data as a big-endian bit stream. Reads past the end of
+// data return zero bits.
+func readBits(data []byte, bitIndex, n int) uint8 {
+	var bits uint8
+	for i := 0; i < n; i++ {
+		pos := bitIndex + i
+		byteIndex := pos / 8
+
+		var bit uint8
+		if byteIndex < len(data) {
+			shift := 7 - uint(pos%8)
+			bit = (data[byteIndex] >> shift) & 1
+		}
+
+		bits = (bits << 1) | bit
+	}
+
+	return bits
+}
+
+// bitAccumulator appends bits written in big-endian order into a byte
+// slice that grows as needed, used by Extract to rebuild the header and
+// payload from a stream of multi-bit reads.
+type bitAccumulator struct {
+	buf []byte
+}
+
+func (acc *bitAccumulator) writeBits(bitIndex int, bits uint8, n int) {
+	for i := 0; i < n; i++ {
+		pos := bitIndex + i
+		byteIndex := pos / 8
+		for byteIndex >= len(acc.buf) {
+			acc.buf = append(acc.buf, 0)
+		}
+
+		bit := (bits >> uint(n-1-i)) & 1
+		shift := 7 - uint(pos%8)
+		acc.buf[byteIndex] |= bit << shift
+	}
+}
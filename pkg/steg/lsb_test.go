@@ -0,0 +1,97 @@
+package steg
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// syntheticImage returns an opaque w x h RGBA image with a simple gradient,
+// big enough to hold a small payload at bpp=1 so every higher bit depth can
+// also be exercised by passing a larger payload.
+func syntheticImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), uint8(x + y), 255})
+		}
+	}
+	return img
+}
+
+func TestLSBCodecRoundTripAtEachBPP(t *testing.T) {
+	// 32x32 pixels gives 32*32*3/8 = 384 bytes of capacity at bpp=1, so
+	// growing the payload forces progressively larger bit depths.
+	img := syntheticImage(32, 32)
+	codec := &LSBCodec{}
+
+	// Chosen so that, with the 12-byte length header Embed adds, each size
+	// lands at a different bit depth: 100 bytes -> bpp=1, 500 -> bpp=2,
+	// 900 -> bpp=3, 1300 -> bpp=4.
+	for _, payloadSize := range []int{100, 500, 900, 1300} {
+		payload := bytes.Repeat([]byte{0x00, 0xFF, 0xAB}, payloadSize/3+1)[:payloadSize]
+
+		encodedImg, err := codec.Embed(img, bytes.NewReader(payload))
+		if err != nil {
+			t.Fatalf("Embed(payloadSize=%d): %v", payloadSize, err)
+		}
+
+		var got bytes.Buffer
+		if err := codec.Extract(encodedImg, &got); err != nil {
+			t.Fatalf("Extract(payloadSize=%d): %v", payloadSize, err)
+		}
+
+		if !bytes.Equal(got.Bytes(), payload) {
+			t.Fatalf("payloadSize=%d: round-trip mismatch: got %d bytes, want %d bytes", payloadSize, got.Len(), len(payload))
+		}
+	}
+}
+
+func TestLSBCodecChannelMask(t *testing.T) {
+	img := syntheticImage(32, 32)
+	codec := &LSBCodec{Channels: ChannelR}
+	payload := []byte("restricted to the red channel")
+
+	encodedImg, err := codec.Embed(img, bytes.NewReader(payload))
+	if err != nil {
+		t.Fatalf("Embed: %v", err)
+	}
+
+	var got bytes.Buffer
+	if err := codec.Extract(encodedImg, &got); err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+
+	if !bytes.Equal(got.Bytes(), payload) {
+		t.Fatalf("round-trip mismatch: got %q, want %q", got.Bytes(), payload)
+	}
+}
+
+func TestGetMinBits(t *testing.T) {
+	pixelCount := 32 * 32
+
+	tests := []struct {
+		totalBytes int
+		wantBpp    int
+	}{
+		{totalBytes: 20, wantBpp: 1},
+		{totalBytes: 384, wantBpp: 1},
+		{totalBytes: 385, wantBpp: 2},
+		{totalBytes: 1536, wantBpp: 4},
+	}
+
+	for _, tt := range tests {
+		bpp, err := getMinBits(pixelCount, 3, tt.totalBytes)
+		if err != nil {
+			t.Fatalf("getMinBits(%d, 3, %d): %v", pixelCount, tt.totalBytes, err)
+		}
+		if bpp != tt.wantBpp {
+			t.Fatalf("getMinBits(%d, 3, %d) = %d, want %d", pixelCount, tt.totalBytes, bpp, tt.wantBpp)
+		}
+	}
+
+	if _, err := getMinBits(pixelCount, 3, 1<<20); err == nil {
+		t.Fatalf("getMinBits with an oversized payload should have failed")
+	}
+}